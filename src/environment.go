@@ -1,13 +1,13 @@
 package polecalc
 
 import (
-	"fmt"
-	"os"
-	"json"
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
-	"reflect"
 	"math"
+	"os"
+	"reflect"
 )
 
 // Holds all the necessary data for evaluating functions in the cuprate system
@@ -81,7 +81,7 @@ func (env *Environment) String() string {
 
 // Construct an Environment from the JSON file with given path.
 // Self-consistent parameters are not set to values given by Init fields.
-func EnvironmentFromFile(filePath string) (*Environment, os.Error) {
+func EnvironmentFromFile(filePath string) (*Environment, error) {
 	fileContents, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return nil, err
@@ -90,7 +90,7 @@ func EnvironmentFromFile(filePath string) (*Environment, os.Error) {
 }
 
 // Convert to string and pass to EnvironmentFromBytes
-func EnvironmentFromString(jsonData string) (*Environment, os.Error) {
+func EnvironmentFromString(jsonData string) (*Environment, error) {
 	jsonBytes, err := StringToBytes(jsonData)
 	if err != nil {
 		return nil, err
@@ -100,7 +100,7 @@ func EnvironmentFromString(jsonData string) (*Environment, os.Error) {
 
 // Construct an Environment from the given JSON byte slice.
 // Self-consistent parameters are not set to values given by Init fields.
-func EnvironmentFromBytes(jsonData []byte) (*Environment, os.Error) {
+func EnvironmentFromBytes(jsonData []byte) (*Environment, error) {
 	jsonObject := make(map[string]interface{})
 	if err := json.Unmarshal(jsonData, &jsonObject); err != nil {
 		return nil, err
@@ -110,7 +110,7 @@ func EnvironmentFromBytes(jsonData []byte) (*Environment, os.Error) {
 
 // Construct an Environment from the given JSON object.
 // Self-consistent parameters are not set to values given by Init fields.
-func EnvironmentFromObject(jsonObject map[string]interface{}) (*Environment, os.Error) {
+func EnvironmentFromObject(jsonObject map[string]interface{}) (*Environment, error) {
 	env := new(Environment)
 	envValue := reflect.Indirect(reflect.ValueOf(env))
 	for key, value := range jsonObject {
@@ -136,9 +136,57 @@ func EnvironmentFromObject(jsonObject map[string]interface{}) (*Environment, os.
 }
 
 // Write the Environment to a JSON file at the given path
-func (env *Environment) WriteToFile(filePath string) os.Error {
+func (env *Environment) WriteToFile(filePath string) error {
 	if err := WriteToJSONFile(env, filePath); err != nil {
 		return err
 	}
 	return nil
 }
+
+// Checkpoint captures enough of a SolveSelfConsistent run to resume it after
+// a crash
+type Checkpoint struct {
+	Iteration  int
+	D1, Mu, F0 float64
+	EpsilonMin float64
+	Residual   float64
+}
+
+// WriteCheckpoint writes a Checkpoint to path as JSON. The write is atomic
+// (path+".tmp" then rename) so a crash mid-write can't corrupt path.
+func (env *Environment) WriteCheckpoint(path string, iteration int, residual float64) error {
+	checkpoint := Checkpoint{iteration, env.D1, env.Mu, env.F0, env.EpsilonMin, residual}
+	tmpPath := path + ".tmp"
+	if err := WriteToJSONFile(checkpoint, tmpPath); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// EnvironmentResumeFromCheckpoint loads the base Environment from envPath,
+// then overrides D1, Mu, F0, and EpsilonMin from the checkpoint at ckptPath
+// instead of the Init* fields.
+func EnvironmentResumeFromCheckpoint(envPath, ckptPath string) (*Environment, error) {
+	env, err := EnvironmentFromFile(envPath)
+	if err != nil {
+		return nil, err
+	}
+	if env.NumProcs <= 0 {
+		env.NumProcs = 1
+	}
+
+	ckptBytes, err := ioutil.ReadFile(ckptPath)
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(ckptBytes, &checkpoint); err != nil {
+		return nil, err
+	}
+
+	env.D1 = checkpoint.D1
+	env.Mu = checkpoint.Mu
+	env.F0 = checkpoint.F0
+	env.EpsilonMin = checkpoint.EpsilonMin
+	return env, nil
+}
@@ -0,0 +1,41 @@
+package polecalc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, "env.json")
+	ckptPath := filepath.Join(dir, "run.ckpt")
+
+	env := &Environment{
+		GridLength: 64,
+		InitD1:     0.1, InitMu: 0.2, InitF0: 0.3,
+		D1: 0.15, Mu: 0.25, F0: 0.35,
+		EpsilonMin: -1.5,
+	}
+	if err := env.WriteToFile(envPath); err != nil {
+		t.Fatalf("WriteToFile: %v", err)
+	}
+	if err := env.WriteCheckpoint(ckptPath, 42, 0.0001); err != nil {
+		t.Fatalf("WriteCheckpoint: %v", err)
+	}
+
+	resumed, err := EnvironmentResumeFromCheckpoint(envPath, ckptPath)
+	if err != nil {
+		t.Fatalf("EnvironmentResumeFromCheckpoint: %v", err)
+	}
+
+	if resumed.GridLength != env.GridLength {
+		t.Errorf("expected GridLength %d from the base environment file, got %d", env.GridLength, resumed.GridLength)
+	}
+	if resumed.D1 != env.D1 || resumed.Mu != env.Mu || resumed.F0 != env.F0 {
+		t.Errorf("expected (D1, Mu, F0) = (%f, %f, %f) from the checkpoint, got (%f, %f, %f)",
+			env.D1, env.Mu, env.F0, resumed.D1, resumed.Mu, resumed.F0)
+	}
+	if resumed.EpsilonMin != env.EpsilonMin {
+		t.Errorf("expected EpsilonMin %f carried over from the checkpoint, got %f", env.EpsilonMin, resumed.EpsilonMin)
+	}
+}
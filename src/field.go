@@ -0,0 +1,163 @@
+package polecalc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ovfControlNumber is written first in the binary data block and checked by
+// ReadOVF to catch endianness mismatches or corruption
+const ovfControlNumber = 123456789012345.0
+
+// ScalarField holds one float64 per point of a Square(pointsPerSide) mesh,
+// indexed (ix, iy) in row-major order.
+type ScalarField struct {
+	PointsPerSide uint32
+	Values        []float64 // Values[iy*PointsPerSide+ix]
+}
+
+func NewScalarField(pointsPerSide uint32) *ScalarField {
+	return &ScalarField{pointsPerSide, make([]float64, uint64(pointsPerSide)*uint64(pointsPerSide))}
+}
+
+func (field *ScalarField) At(ix, iy uint32) float64 {
+	return field.Values[iy*field.PointsPerSide+ix]
+}
+
+func (field *ScalarField) Set(ix, iy uint32, value float64) {
+	field.Values[iy*field.PointsPerSide+ix] = value
+}
+
+// VectorField holds ValueDim float64 components per point of a
+// Square(pointsPerSide) mesh, indexed (ix, iy) in row-major order.
+type VectorField struct {
+	PointsPerSide uint32
+	ValueDim      uint32
+	Values        []float64 // Values[(iy*PointsPerSide+ix)*ValueDim+component]
+}
+
+func NewVectorField(pointsPerSide, valueDim uint32) *VectorField {
+	numValues := uint64(pointsPerSide) * uint64(pointsPerSide) * uint64(valueDim)
+	return &VectorField{pointsPerSide, valueDim, make([]float64, numValues)}
+}
+
+func (field *VectorField) At(ix, iy uint32) []float64 {
+	start := (iy*field.PointsPerSide + ix) * field.ValueDim
+	return field.Values[start : start+field.ValueDim]
+}
+
+func (field *VectorField) Set(ix, iy uint32, value []float64) {
+	copy(field.At(ix, iy), value)
+}
+
+// WriteOVF writes field to path in an OOMMF-style OVF layout: an ASCII
+// header (with env as JSON on the Desc: line) followed by a binary data
+// section
+func WriteOVF(path string, field *ScalarField, env *Environment) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	envJSON, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	step := 2 * math.Pi / float64(field.PointsPerSide)
+	writer := bufio.NewWriter(file)
+	fmt.Fprintf(writer, "# OVF-style field export (polecalc)\n")
+	fmt.Fprintf(writer, "# xnodes: %d\n", field.PointsPerSide)
+	fmt.Fprintf(writer, "# ynodes: %d\n", field.PointsPerSide)
+	fmt.Fprintf(writer, "# znodes: 1\n")
+	fmt.Fprintf(writer, "# xstepsize: %g\n", step)
+	fmt.Fprintf(writer, "# ystepsize: %g\n", step)
+	fmt.Fprintf(writer, "# valuedim: 1\n")
+	fmt.Fprintf(writer, "# valueunits: 1\n")
+	fmt.Fprintf(writer, "# Desc: %s\n", string(envJSON))
+	fmt.Fprintf(writer, "# Begin: Data Binary 8\n")
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if err := binary.Write(file, binary.LittleEndian, ovfControlNumber); err != nil {
+		return err
+	}
+	if err := binary.Write(file, binary.LittleEndian, field.Values); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(file, "\n# End: Data Binary 8\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReadOVF reads a file written by WriteOVF, returning the field and the raw
+// header fields keyed by name (including "Desc", the Environment JSON)
+func ReadOVF(path string) (*ScalarField, map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header := make(map[string]string)
+	for {
+		line, readErr := reader.ReadString('\n')
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		if strings.HasPrefix(trimmed, "Begin: Data Binary 8") {
+			break
+		}
+		if colon := strings.Index(trimmed, ":"); colon >= 0 {
+			key := strings.TrimSpace(trimmed[:colon])
+			value := strings.TrimSpace(trimmed[colon+1:])
+			header[key] = value
+		}
+		if readErr != nil {
+			return nil, nil, fmt.Errorf("ReadOVF: %s: missing \"# Begin: Data Binary 8\" section", path)
+		}
+	}
+
+	xnodes, err := strconv.ParseUint(header["xnodes"], 10, 32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ReadOVF: %s: bad or missing xnodes: %v", path, err)
+	}
+	ynodes, err := strconv.ParseUint(header["ynodes"], 10, 32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ReadOVF: %s: bad or missing ynodes: %v", path, err)
+	}
+	valueDim := uint64(1)
+	if dim, ok := header["valuedim"]; ok {
+		parsed, err := strconv.ParseUint(dim, 10, 32)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ReadOVF: %s: bad valuedim: %v", path, err)
+		}
+		valueDim = parsed
+	}
+	if valueDim != 1 {
+		return nil, nil, fmt.Errorf("ReadOVF: %s: valuedim %d not supported, ReadOVF only reads ScalarField (valuedim 1); use a VectorField reader instead", path, valueDim)
+	}
+
+	var control float64
+	if err := binary.Read(reader, binary.LittleEndian, &control); err != nil {
+		return nil, nil, err
+	}
+	if control != ovfControlNumber {
+		return nil, nil, fmt.Errorf("ReadOVF: %s: control number mismatch (got %v, want %v) - endianness or corruption", path, control, ovfControlNumber)
+	}
+
+	values := make([]float64, xnodes*ynodes*valueDim)
+	if err := binary.Read(reader, binary.LittleEndian, values); err != nil {
+		return nil, nil, err
+	}
+
+	return &ScalarField{uint32(xnodes), values}, header, nil
+}
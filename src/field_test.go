@@ -0,0 +1,64 @@
+package polecalc
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOVFRoundTrip(t *testing.T) {
+	const pointsPerSide = 8
+	field := NewScalarField(pointsPerSide)
+	for iy := uint32(0); iy < pointsPerSide; iy++ {
+		for ix := uint32(0); ix < pointsPerSide; ix++ {
+			field.Set(ix, iy, float64(ix)+10*float64(iy))
+		}
+	}
+	env := &Environment{GridLength: pointsPerSide, InitD1: 0.1, InitMu: -0.2, InitF0: 0.3}
+
+	path := filepath.Join(t.TempDir(), "field.ovf")
+	if err := WriteOVF(path, field, env); err != nil {
+		t.Fatalf("WriteOVF: %v", err)
+	}
+
+	readField, header, err := ReadOVF(path)
+	if err != nil {
+		t.Fatalf("ReadOVF: %v", err)
+	}
+	if readField.PointsPerSide != pointsPerSide {
+		t.Errorf("expected PointsPerSide %d, got %d", pointsPerSide, readField.PointsPerSide)
+	}
+	for iy := uint32(0); iy < pointsPerSide; iy++ {
+		for ix := uint32(0); ix < pointsPerSide; ix++ {
+			want := field.At(ix, iy)
+			got := readField.At(ix, iy)
+			if math.Abs(want-got) > 0 {
+				t.Errorf("At(%d, %d): expected %f, got %f", ix, iy, want, got)
+			}
+		}
+	}
+	if _, ok := header["Desc"]; !ok {
+		t.Errorf("expected Desc header carrying the Environment JSON")
+	}
+}
+
+func TestReadOVFRejectsCorruptControlNumber(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corrupt.ovf")
+	if err := os.WriteFile(path, []byte("# xnodes: 2\n# ynodes: 2\n# Begin: Data Binary 8\n\x00\x00\x00\x00\x00\x00\x00\x00"), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	if _, _, err := ReadOVF(path); err == nil {
+		t.Errorf("expected ReadOVF to reject a file with a bad control number")
+	}
+}
+
+func TestReadOVFRejectsVectorDim(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vector.ovf")
+	if err := os.WriteFile(path, []byte("# xnodes: 2\n# ynodes: 2\n# valuedim: 3\n# Begin: Data Binary 8\n"), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	if _, _, err := ReadOVF(path); err == nil {
+		t.Errorf("expected ReadOVF to reject valuedim != 1, since it only reads ScalarField")
+	}
+}
@@ -9,6 +9,8 @@ type Consumer func(point []float64) float64
 type GridListener interface {
 	initialize() GridListener
 	grab(point []float64) GridListener
+	// Merge other workers' partial state into this listener's state
+	Combine(others []GridListener) GridListener
 	result() interface{}
 }
 
@@ -49,6 +51,17 @@ func (accum Accumulator) result() interface{} {
 	return accum.value / float64(accum.points)
 }
 
+// Combine sums other workers' partial sums, Kahan compensation included
+func (accum Accumulator) Combine(others []GridListener) GridListener {
+	for _, other := range others {
+		o := other.(Accumulator)
+		accum.value, accum.compensate = KahanSum(o.value, accum.value, accum.compensate)
+		accum.value, accum.compensate = KahanSum(o.compensate, accum.value, accum.compensate)
+		accum.points += o.points
+	}
+	return accum
+}
+
 // Create a new accumulator
 func NewAccumulator(worker Consumer) *Accumulator {
 	accum := new(Accumulator)
@@ -80,6 +93,17 @@ func (minData MinimumData) result() interface{} {
 	return minData.minimum
 }
 
+// Combine keeps the smallest minimum across all workers
+func (minData MinimumData) Combine(others []GridListener) GridListener {
+	for _, other := range others {
+		o := other.(MinimumData)
+		if o.minimum < minData.minimum {
+			minData.minimum = o.minimum
+		}
+	}
+	return minData
+}
+
 func NewMinimumData(worker Consumer) *MinimumData {
 	minData := new(MinimumData)
 	minData.worker = worker
@@ -112,6 +136,17 @@ func (maxData MaximumData) result() interface{} {
 	return maxData.maximum
 }
 
+// Combine keeps the largest maximum across all workers
+func (maxData MaximumData) Combine(others []GridListener) GridListener {
+	for _, other := range others {
+		o := other.(MaximumData)
+		if o.maximum > maxData.maximum {
+			maxData.maximum = o.maximum
+		}
+	}
+	return maxData
+}
+
 func NewMaximumData(worker Consumer) *MaximumData {
 	maxData := new(MaximumData)
 	maxData.worker = worker
@@ -122,53 +157,214 @@ func NewMaximumData(worker Consumer) *MaximumData {
 // --- accumulator for (discrete approximation) delta functions ---
 type DeltaTermsFunc func(q []float64) ([]float64, []float64)
 
+// KernelType selects how a (energy, weight) delta term is smeared across bins
+type KernelType int
+
+const (
+	NearestBinKernel KernelType = iota // whole weight goes to the closest bin
+	LinearKernel                       // weight split linearly between the two neighboring bins
+	GaussianKernel                     // weight spread with a Gaussian of the given Width
+	LorentzianKernel                   // weight spread with a Lorentzian of the given Width
+)
+
+// Number of widths (std. devs for Gaussian, HWHM for Lorentzian) to spread
+// a Gaussian/Lorentzian kernel before truncating it
+const kernelTruncWidths = 4.0
+
 type DeltaBinner struct {
 	DeltaTerms        DeltaTermsFunc
 	BinStart, BinStop float64
 	NumBins           uint
-	Bins              []float64 // value of the function at various omega values
-	Compensates       []float64 // compensation values for Kahan summation
+	Kernel            KernelType // smoothing kernel to use when binning terms
+	Width             float64    // kernel width; unused for NearestBinKernel/LinearKernel
+	Bins              []float64  // value of the function at various omega values
+	Compensates       []float64  // compensation values for Kahan summation
 	NumPoints         uint64
 }
 
+// initialize allocates fresh Bins/Compensates so each worker gets its own
 func (binner DeltaBinner) initialize() GridListener {
-	return nil
+	binner.Bins = make([]float64, binner.NumBins)
+	binner.Compensates = make([]float64, binner.NumBins)
+	binner.NumPoints = 0
+	return binner
 }
 
 func (binner DeltaBinner) grab(point []float64) GridListener {
-	return nil
+	energies, weights := binner.DeltaTerms(point)
+	for i, energy := range energies {
+		binner.deposit(energy, weights[i])
+	}
+	binner.NumPoints++
+	return binner
+}
+
+// DeltaBinnerResult is the GridListener result; DeltaSum unpacks it
+type DeltaBinnerResult struct {
+	Omegas, Values []float64
+}
+
+func (binner DeltaBinner) result() interface{} {
+	width := binner.binWidth()
+	omegas := make([]float64, binner.NumBins)
+	values := make([]float64, binner.NumBins)
+	for i := range binner.Bins {
+		omegas[i] = binner.BinStart + (float64(i)+0.5)*width
+		values[i] = binner.Bins[i] / (float64(binner.NumPoints) * width)
+	}
+	return DeltaBinnerResult{omegas, values}
+}
+
+// Combine sums other workers' bins, per-bin, Kahan compensation included
+func (binner DeltaBinner) Combine(others []GridListener) GridListener {
+	for _, other := range others {
+		o := other.(DeltaBinner)
+		for i := range binner.Bins {
+			binner.Bins[i], binner.Compensates[i] = KahanSum(o.Bins[i], binner.Bins[i], binner.Compensates[i])
+			binner.Bins[i], binner.Compensates[i] = KahanSum(o.Compensates[i], binner.Bins[i], binner.Compensates[i])
+		}
+		binner.NumPoints += o.NumPoints
+	}
+	return binner
+}
+
+func (binner DeltaBinner) binWidth() float64 {
+	return (binner.BinStop - binner.BinStart) / float64(binner.NumBins)
+}
+
+// addToBin folds weight into Bins[idx], ignoring out-of-range indices
+func (binner DeltaBinner) addToBin(idx int, weight float64) {
+	if idx < 0 || idx >= int(binner.NumBins) {
+		return
+	}
+	binner.Bins[idx], binner.Compensates[idx] = KahanSum(weight, binner.Bins[idx], binner.Compensates[idx])
 }
 
-func (binner DeltaBinner) result() ([]float64, []float64) {
-	return nil, nil
+// deposit distributes a single (energy, weight) delta term into Bins
+// according to binner.Kernel
+func (binner DeltaBinner) deposit(energy, weight float64) {
+	width := binner.binWidth()
+	switch binner.Kernel {
+	case LinearKernel:
+		pos := (energy - binner.BinStart) / width
+		lo := int(math.Floor(pos))
+		frac := pos - float64(lo)
+		binner.addToBin(lo, weight*(1.0-frac))
+		binner.addToBin(lo+1, weight*frac)
+	case GaussianKernel:
+		binner.depositSmoothed(energy, weight, width, gaussianKernel)
+	case LorentzianKernel:
+		binner.depositSmoothed(energy, weight, width, lorentzianKernel)
+	default: // NearestBinKernel
+		idx := int(math.Floor((energy-binner.BinStart)/width + 0.5))
+		binner.addToBin(idx, weight)
+	}
 }
 
-func NewDeltaBinner(deltaTerms DeltaTermsFunc, binStart, binStop float64, numBins uint) *DeltaBinner {
+// depositSmoothed spreads weight across bins within kernelTruncWidths of
+// energy, renormalized by the mass actually captured so truncation (e.g. the
+// Lorentzian's tails) doesn't lose weight
+func (binner DeltaBinner) depositSmoothed(energy, weight, width float64, kernel func(x, width float64) float64) {
+	lo := int(math.Floor((energy - kernelTruncWidths*binner.Width - binner.BinStart) / width))
+	hi := int(math.Ceil((energy + kernelTruncWidths*binner.Width - binner.BinStart) / width))
+
+	kernelValues := make([]float64, hi-lo+1)
+	capturedMass := 0.0
+	for i := range kernelValues {
+		idx := lo + i
+		center := binner.BinStart + (float64(idx)+0.5)*width
+		kernelValues[i] = kernel(center-energy, binner.Width)
+		capturedMass += kernelValues[i] * width
+	}
+	if capturedMass <= 0 {
+		return
+	}
+	for i, kernelValue := range kernelValues {
+		binner.addToBin(lo+i, weight*kernelValue*width/capturedMass)
+	}
+}
+
+func gaussianKernel(x, width float64) float64 {
+	return math.Exp(-0.5*x*x/(width*width)) / (width * math.Sqrt(2.0*math.Pi))
+}
+
+func lorentzianKernel(x, width float64) float64 {
+	return (width / math.Pi) / (x*x + width*width)
+}
+
+func NewDeltaBinner(deltaTerms DeltaTermsFunc, binStart, binStop float64, numBins uint, kernel KernelType, width float64) *DeltaBinner {
 	// each value will be initialized to 0 (that's what we want)
 	bins, compensates := make([]float64, numBins), make([]float64, numBins)
-	binner := &DeltaBinner{deltaTerms, binStart, binStop, numBins, bins, compensates, 0.0}
+	binner := &DeltaBinner{deltaTerms, binStart, binStop, numBins, kernel, width, bins, compensates, 0}
 	return binner
 }
 
+// --- collector that fills a ScalarField ---
+// FieldCollector fills a ScalarField with grab() results in grab order;
+// only safe with numWorkers == 1 (see CollectField)
+type FieldCollector struct {
+	Worker Consumer
+	Field  *ScalarField
+	next   uint32 // next index in Field.Values to fill
+}
+
+func (collector FieldCollector) initialize() GridListener {
+	collector.Field = NewScalarField(collector.Field.PointsPerSide)
+	collector.next = 0
+	return collector
+}
+
+func (collector FieldCollector) grab(point []float64) GridListener {
+	collector.Field.Values[collector.next] = collector.Worker(point)
+	collector.next++
+	return collector
+}
+
+func (collector FieldCollector) result() interface{} {
+	return collector.Field
+}
+
+// Combine only supports the single-worker case FieldCollector is meant for
+func (collector FieldCollector) Combine(others []GridListener) GridListener {
+	if len(others) > 0 {
+		panic("FieldCollector.Combine: FieldCollector only supports numWorkers == 1")
+	}
+	return collector
+}
+
+func NewFieldCollector(worker Consumer, pointsPerSide uint32) *FieldCollector {
+	return &FieldCollector{worker, NewScalarField(pointsPerSide), 0}
+}
+
+// CollectField evaluates worker over a square grid into a ScalarField,
+// always single-threaded so grab order matches the field's (ix, iy) layout
+func CollectField(pointsPerSide uint32, worker Consumer) *ScalarField {
+	collector := NewFieldCollector(worker, pointsPerSide)
+	return DoGridListen(pointsPerSide, 1, *collector).(*ScalarField)
+}
+
 // -- utility functions --
 // assumes numWorkers > 0
+// Each worker gets its own private listener and consumes cmesh
+// independently; partials are merged with Combine once all are done.
 func DoGridListen(pointsPerSide uint32, numWorkers uint16, listener GridListener) interface{} {
 	cmesh := Square(pointsPerSide)
-	done := make(chan bool)
-	listener = listener.initialize()
+	partials := make(chan GridListener, numWorkers)
 	var i uint16 = 0
 	for i = 0; i < numWorkers; i++ {
 		go func() {
+			worker := listener.initialize()
 			for point, ok := <-cmesh; ok; point, ok = <-cmesh {
-				listener = listener.grab(point)
+				worker = worker.grab(point)
 			}
-			done <- true
+			partials <- worker
 		}()
 	}
-	for doneCount := 0; doneCount < int(numWorkers); doneCount++ {
-		<-done
+	combined := <-partials
+	for doneCount := 1; doneCount < int(numWorkers); doneCount++ {
+		combined = combined.Combine([]GridListener{<-partials})
 	}
-	return listener.result()
+	return combined.result()
 }
 
 // Find the average over a square grid of the function given by worker.
@@ -190,3 +386,14 @@ func Maximum(pointsPerSide uint32, worker Consumer, numWorkers uint16) float64 {
 	maxData := NewMaximumData(worker)
 	return DoGridListen(pointsPerSide, numWorkers, *maxData).(float64)
 }
+
+// Bin the (energy, weight) terms given by deltaTerms over a square grid into
+// a spectral function: a histogram over [binStart, binStop] with numBins
+// bins, smoothed with the given kernel, and normalized so the result
+// approximates a density of states / spectral function A(q, omega).
+// Returns (omegas, values) where omegas are bin centers.
+func DeltaSum(pointsPerSide uint32, deltaTerms DeltaTermsFunc, binStart, binStop float64, numBins uint, kernel KernelType, width float64, numWorkers uint16) ([]float64, []float64) {
+	binner := NewDeltaBinner(deltaTerms, binStart, binStop, numBins, kernel, width)
+	result := DoGridListen(pointsPerSide, numWorkers, *binner).(DeltaBinnerResult)
+	return result.Omegas, result.Values
+}
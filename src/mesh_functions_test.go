@@ -0,0 +1,80 @@
+package polecalc
+
+import (
+	"math"
+	"testing"
+)
+
+// tightBindingDeltaTerms returns a DeltaTermsFunc for the 2D tight-binding
+// band epsilon(k) = -2*t*(cos(kx) + cos(ky)), used here as a known-analytic
+// density of states to check DeltaSum against.
+func tightBindingDeltaTerms(t float64) DeltaTermsFunc {
+	return func(q []float64) ([]float64, []float64) {
+		energy := -2.0 * t * (math.Cos(q[0]) + math.Cos(q[1]))
+		return []float64{energy}, []float64{1.0}
+	}
+}
+
+func TestDeltaSumTightBindingNormalization(t *testing.T) {
+	omegas, values := DeltaSum(128, tightBindingDeltaTerms(1.0), -4.2, 4.2, 200, GaussianKernel, 0.05, 4)
+	if len(omegas) != 200 || len(values) != 200 {
+		t.Fatalf("expected 200 bins, got %d omegas and %d values", len(omegas), len(values))
+	}
+	binWidth := omegas[1] - omegas[0]
+	total := 0.0
+	for _, v := range values {
+		total += v * binWidth
+	}
+	if math.Abs(total-1.0) > 0.05 {
+		t.Errorf("expected normalized DOS to integrate to ~1, got %f", total)
+	}
+
+	// The 2D tight-binding DOS has a logarithmic van Hove singularity at
+	// omega = 0; density there should dwarf the density near the band edges.
+	var peak, edge float64
+	for i, omega := range omegas {
+		if math.Abs(omega) < binWidth {
+			peak = values[i]
+		}
+		if math.Abs(omega+4.0) < binWidth {
+			edge = values[i]
+		}
+	}
+	if peak < 3*edge {
+		t.Errorf("expected van Hove peak near omega=0 (%f) to dwarf band-edge density (%f)", peak, edge)
+	}
+}
+
+func TestDeltaBinnerKernelsConserveWeight(t *testing.T) {
+	for _, kernel := range []KernelType{NearestBinKernel, LinearKernel, GaussianKernel, LorentzianKernel} {
+		binner := NewDeltaBinner(func(q []float64) ([]float64, []float64) {
+			return []float64{0.37}, []float64{2.0}
+		}, -1.0, 1.0, 100, kernel, 0.02)
+		listener := binner.initialize()
+		listener = listener.grab([]float64{0, 0})
+		result := listener.result().(DeltaBinnerResult)
+
+		binWidth := result.Omegas[1] - result.Omegas[0]
+		total := 0.0
+		for _, v := range result.Values {
+			total += v * binWidth
+		}
+		if math.Abs(total-2.0) > 0.05 {
+			t.Errorf("kernel %d: expected deposited weight to be conserved (~2.0), got %f", kernel, total)
+		}
+	}
+}
+
+// TestAverageMatchesAcrossWorkerCounts guards against the data race where
+// DoGridListen workers shared a single listener value: Average() must give
+// the same result regardless of how many workers split the grid.
+func TestAverageMatchesAcrossWorkerCounts(t *testing.T) {
+	worker := func(point []float64) float64 {
+		return point[0] + 2*point[1]
+	}
+	serial := Average(64, worker, 1)
+	parallel := Average(64, worker, 8)
+	if math.Abs(serial-parallel) > 1e-9 {
+		t.Errorf("expected Average to be independent of worker count, got serial=%f parallel=%f", serial, parallel)
+	}
+}
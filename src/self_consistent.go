@@ -0,0 +1,209 @@
+package polecalc
+
+import (
+	"fmt"
+	"math"
+)
+
+// Nelder-Mead downhill simplex parameters (see Wikipedia's "Nelder-Mead
+// method" for the standard names)
+const (
+	selfConsistentAlpha   = 1.0  // reflection coefficient
+	selfConsistentGamma   = 2.0  // expansion coefficient
+	selfConsistentRho     = 0.5  // contraction coefficient
+	selfConsistentSigma   = 0.5  // shrink coefficient
+	selfConsistentPerturb = 0.05 // fraction used to build the initial simplex
+	selfConsistentMaxIter = 500
+	selfConsistentTol     = 1e-10
+)
+
+// SolveSelfConsistent finds D1, Mu, and F0 simultaneously by minimizing
+// D1err^2 + MuErr^2 + F0err^2 with a Nelder-Mead downhill simplex.
+// tol/maxIter <= 0 fall back to defaults. checkpointPath/checkpointEvery
+// control periodic checkpointing; checkpointEvery <= 0 disables it.
+func SolveSelfConsistent(env *Environment, tol float64, maxIter int, checkpointPath string, checkpointEvery int) (residual float64, iterations int, err error) {
+	if tol <= 0 {
+		tol = selfConsistentTol
+	}
+	if maxIter <= 0 {
+		maxIter = selfConsistentMaxIter
+	}
+
+	objective := func(x [3]float64) float64 {
+		env.D1, env.Mu, env.F0 = x[0], x[1], x[2]
+		// EpsilonMin is cached on Environment but depends on D1, so it must
+		// be refreshed on every evaluation
+		env.EpsilonMin = EpsilonMin(*env)
+		d1Err := ZeroTempD1AbsError(*env)
+		muErr := ZeroTempMuAbsError(*env)
+		f0Err := ZeroTempF0AbsError(*env)
+		return d1Err*d1Err + muErr*muErr + f0Err*f0Err
+	}
+
+	var checkpointErr error
+	onStep := func(iteration int, best [3]float64, bestValue float64) {
+		if checkpointPath == "" || checkpointEvery <= 0 || iteration%checkpointEvery != 0 {
+			return
+		}
+		objective(best) // refresh env to match the vertex being checkpointed
+		if writeErr := env.WriteCheckpoint(checkpointPath, iteration, math.Sqrt(bestValue)); writeErr != nil {
+			checkpointErr = fmt.Errorf("SolveSelfConsistent: failed to write checkpoint: %v", writeErr)
+		}
+	}
+
+	start := [3]float64{env.InitD1, env.InitMu, env.InitF0}
+	best, value, iterations, converged := nelderMead3D(objective, start, tol, maxIter, onStep)
+	objective(best) // leave env holding the best parameters found
+	residual = math.Sqrt(value)
+	if checkpointErr != nil {
+		return residual, iterations, checkpointErr
+	}
+	if !converged {
+		return residual, iterations, fmt.Errorf("SolveSelfConsistent: did not converge within %d iterations (residual %g)", maxIter, residual)
+	}
+	return residual, iterations, nil
+}
+
+// nelderMead3D minimizes objective over R^3, stopping once the simplex
+// diameter and value spread both fall below tol or maxIter is hit. onStep,
+// if non-nil, is called with the current best vertex each iteration.
+func nelderMead3D(objective func([3]float64) float64, start [3]float64, tol float64, maxIter int, onStep func(iteration int, best [3]float64, bestValue float64)) (best [3]float64, bestValue float64, iterations int, converged bool) {
+	var simplex [4][3]float64
+	var values [4]float64
+	simplex[0] = start
+	for i := 0; i < 3; i++ {
+		vertex := start
+		if vertex[i] == 0 {
+			vertex[i] = selfConsistentPerturb
+		} else {
+			vertex[i] *= 1 + selfConsistentPerturb
+		}
+		simplex[i+1] = vertex
+	}
+	for i, vertex := range simplex {
+		values[i] = objective(vertex)
+	}
+
+	for iterations = 0; iterations < maxIter; iterations++ {
+		sortSimplex(&simplex, &values)
+
+		if onStep != nil {
+			onStep(iterations, simplex[0], values[0])
+		}
+
+		if simplexDiameter(simplex) < tol && (values[3]-values[0]) < tol {
+			return simplex[0], values[0], iterations, true
+		}
+
+		centroid := simplexCentroid(simplex)
+		worst := simplex[3]
+
+		reflected := reflectVertex(centroid, worst, selfConsistentAlpha)
+		reflectedValue := objective(reflected)
+
+		switch {
+		case reflectedValue < values[0]:
+			expanded := expand(centroid, reflected, selfConsistentGamma)
+			expandedValue := objective(expanded)
+			if expandedValue < reflectedValue {
+				simplex[3], values[3] = expanded, expandedValue
+			} else {
+				simplex[3], values[3] = reflected, reflectedValue
+			}
+		case reflectedValue < values[2]:
+			simplex[3], values[3] = reflected, reflectedValue
+		default:
+			contracted := contract(centroid, worst, selfConsistentRho)
+			contractedValue := objective(contracted)
+			if contractedValue < values[3] {
+				simplex[3], values[3] = contracted, contractedValue
+			} else {
+				for i := 1; i < 4; i++ {
+					simplex[i] = shrinkToward(simplex[0], simplex[i], selfConsistentSigma)
+					values[i] = objective(simplex[i])
+				}
+			}
+		}
+	}
+
+	sortSimplex(&simplex, &values)
+	return simplex[0], values[0], iterations, false
+}
+
+// sortSimplex orders simplex/values from best (lowest objective value, index
+// 0) to worst (index 3) via insertion sort, which is plenty for 4 vertices
+func sortSimplex(simplex *[4][3]float64, values *[4]float64) {
+	for i := 1; i < 4; i++ {
+		for j := i; j > 0 && values[j] < values[j-1]; j-- {
+			simplex[j], simplex[j-1] = simplex[j-1], simplex[j]
+			values[j], values[j-1] = values[j-1], values[j]
+		}
+	}
+}
+
+// simplexCentroid returns the centroid of all but the worst (last) vertex
+func simplexCentroid(simplex [4][3]float64) [3]float64 {
+	var centroid [3]float64
+	for i := 0; i < 3; i++ {
+		for k := 0; k < 3; k++ {
+			centroid[k] += simplex[i][k]
+		}
+	}
+	for k := range centroid {
+		centroid[k] /= 3.0
+	}
+	return centroid
+}
+
+func simplexDiameter(simplex [4][3]float64) float64 {
+	diameter := 0.0
+	for i := 1; i < 4; i++ {
+		d := vectorDistance(simplex[0], simplex[i])
+		if d > diameter {
+			diameter = d
+		}
+	}
+	return diameter
+}
+
+func reflectVertex(centroid, worst [3]float64, alpha float64) [3]float64 {
+	var point [3]float64
+	for i := range point {
+		point[i] = centroid[i] + alpha*(centroid[i]-worst[i])
+	}
+	return point
+}
+
+func expand(centroid, reflected [3]float64, gamma float64) [3]float64 {
+	var point [3]float64
+	for i := range point {
+		point[i] = centroid[i] + gamma*(reflected[i]-centroid[i])
+	}
+	return point
+}
+
+func contract(centroid, worst [3]float64, rho float64) [3]float64 {
+	var point [3]float64
+	for i := range point {
+		point[i] = centroid[i] + rho*(worst[i]-centroid[i])
+	}
+	return point
+}
+
+// shrinkToward pulls vertex toward best by sigma
+func shrinkToward(best, vertex [3]float64, sigma float64) [3]float64 {
+	var point [3]float64
+	for i := range point {
+		point[i] = best[i] + sigma*(vertex[i]-best[i])
+	}
+	return point
+}
+
+func vectorDistance(a, b [3]float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,30 @@
+package polecalc
+
+import (
+	"math"
+	"testing"
+)
+
+// TestNelderMead3DFindsQuadraticMinimum checks the generic simplex core
+// against a known-analytic minimum, independent of the Environment-specific
+// objective used by SolveSelfConsistent.
+func TestNelderMead3DFindsQuadraticMinimum(t *testing.T) {
+	target := [3]float64{1.0, -2.0, 0.5}
+	objective := func(x [3]float64) float64 {
+		dx, dy, dz := x[0]-target[0], x[1]-target[1], x[2]-target[2]
+		return dx*dx + dy*dy + dz*dz
+	}
+
+	best, value, iterations, converged := nelderMead3D(objective, [3]float64{0, 0, 0}, 1e-12, 500, nil)
+	if !converged {
+		t.Fatalf("expected convergence within 500 iterations, ran %d", iterations)
+	}
+	if value > 1e-8 {
+		t.Errorf("expected objective near 0 at minimum, got %g", value)
+	}
+	for i := range best {
+		if math.Abs(best[i]-target[i]) > 1e-4 {
+			t.Errorf("coordinate %d: expected %g, got %g", i, target[i], best[i])
+		}
+	}
+}